@@ -0,0 +1,128 @@
+// Package metrics provides Prometheus instrumentation for the HTTP
+// gateway's upload endpoint.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "neofs_http_gw"
+	subsystem = "upload"
+)
+
+// Collector holds every metric exposed by the upload endpoint. A nil
+// *Collector is valid and every method on it is a no-op, so instrumentation
+// can be wired in unconditionally.
+type Collector struct {
+	requestsTotal   *prometheus.CounterVec
+	duration        prometheus.Histogram
+	payloadSize     prometheus.Histogram
+	inFlight        prometheus.Gauge
+	multipartErrors prometheus.Counter
+	putErrors       prometheus.Counter
+}
+
+// NewCollector creates a Collector and registers its metrics with reg.
+func NewCollector(reg prometheus.Registerer) *Collector {
+	factory := promauto.With(reg)
+	return &Collector{
+		requestsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "requests_total",
+			Help:      "Total number of upload requests by container ID and HTTP response status.",
+		}, []string{"cid", "status"}),
+		duration: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "duration_seconds",
+			Help:      "Duration of a single object upload.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		payloadSize: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "payload_size_bytes",
+			Help:      "Size of object payloads written to NeoFS.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+		}),
+		inFlight: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "in_flight_requests",
+			Help:      "Number of upload requests currently being processed.",
+		}),
+		multipartErrors: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "multipart_errors_total",
+			Help:      "Total number of multipart/form-data parsing errors.",
+		}),
+		putErrors: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "put_errors_total",
+			Help:      "Total number of NeoFS PutObject errors.",
+		}),
+	}
+}
+
+// ObservePartUpload records how long a single multipart part's PutObject
+// took and how many payload bytes were streamed for it.
+func (c *Collector) ObservePartUpload(dur time.Duration, size int64) {
+	if c == nil {
+		return
+	}
+	c.duration.Observe(dur.Seconds())
+	c.payloadSize.Observe(float64(size))
+}
+
+// ObserveRequest records the outcome of a whole upload request: the
+// container it went into and the HTTP status code returned to the client.
+func (c *Collector) ObserveRequest(cid string, httpStatus int) {
+	if c == nil {
+		return
+	}
+	c.requestsTotal.WithLabelValues(cid, strconv.Itoa(httpStatus)).Inc()
+}
+
+// IncInFlight increments the in-flight request gauge.
+func (c *Collector) IncInFlight() {
+	if c != nil {
+		c.inFlight.Inc()
+	}
+}
+
+// DecInFlight decrements the in-flight request gauge.
+func (c *Collector) DecInFlight() {
+	if c != nil {
+		c.inFlight.Dec()
+	}
+}
+
+// IncMultipartError counts a multipart/form-data parsing error.
+func (c *Collector) IncMultipartError() {
+	if c != nil {
+		c.multipartErrors.Inc()
+	}
+}
+
+// IncPutError counts a NeoFS PutObject error.
+func (c *Collector) IncPutError() {
+	if c != nil {
+		c.putErrors.Inc()
+	}
+}
+
+// Handler returns the promhttp handler serving the metrics registered with
+// reg, for mounting on a configurable endpoint such as /metrics.
+func Handler(reg *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(reg, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,88 @@
+// Package resolver resolves human-readable container names into container
+// IDs for handlers that accept either one in place of a raw CID.
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/container"
+)
+
+// ContainerResolver resolves name into a container ID.
+type ContainerResolver interface {
+	Resolve(ctx context.Context, name string) (*container.ID, error)
+}
+
+// NNSClient is the subset of the NeoFS NNS contract client needed to look up
+// a container ID by its registered name.
+type NNSClient interface {
+	ResolveContainerName(ctx context.Context, name string) (*container.ID, error)
+}
+
+// NewNNSResolver returns a ContainerResolver backed by the NeoFS NNS contract.
+func NewNNSResolver(cli NNSClient) ContainerResolver {
+	return &nnsResolver{cli: cli}
+}
+
+type nnsResolver struct {
+	cli NNSClient
+}
+
+func (r *nnsResolver) Resolve(ctx context.Context, name string) (*container.ID, error) {
+	return r.cli.ResolveContainerName(ctx, name)
+}
+
+const dnsTXTPrefix = "container="
+
+// NewDNSResolver returns a ContainerResolver that looks up a TXT record of
+// the form "container=<id>" for "<name>.<zone>".
+func NewDNSResolver(zone string) ContainerResolver {
+	return &dnsResolver{zone: zone}
+}
+
+type dnsResolver struct {
+	zone string
+}
+
+func (r *dnsResolver) Resolve(ctx context.Context, name string) (*container.ID, error) {
+	records, err := net.DefaultResolver.LookupTXT(ctx, name+"."+r.zone)
+	if err != nil {
+		return nil, fmt.Errorf("dns lookup: %w", err)
+	}
+	for _, rec := range records {
+		if !strings.HasPrefix(rec, dnsTXTPrefix) {
+			continue
+		}
+		id := container.NewID()
+		if err := id.Parse(strings.TrimPrefix(rec, dnsTXTPrefix)); err != nil {
+			return nil, fmt.Errorf("invalid container id in TXT record: %w", err)
+		}
+		return id, nil
+	}
+	return nil, fmt.Errorf("no container TXT record found for %q", name)
+}
+
+// NewChain returns a ContainerResolver that tries each of resolvers in order
+// and returns the first successful resolution.
+func NewChain(resolvers ...ContainerResolver) ContainerResolver {
+	return chain(resolvers)
+}
+
+type chain []ContainerResolver
+
+func (c chain) Resolve(ctx context.Context, name string) (*container.ID, error) {
+	if len(c) == 0 {
+		return nil, fmt.Errorf("no resolvers configured")
+	}
+	var err error
+	for _, r := range c {
+		var id *container.ID
+		if id, err = r.Resolve(ctx, name); err == nil {
+			return id, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,54 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/container"
+)
+
+type fakeResolver struct {
+	id  *container.ID
+	err error
+}
+
+func (f *fakeResolver) Resolve(_ context.Context, _ string) (*container.ID, error) {
+	return f.id, f.err
+}
+
+func TestChainResolvesFromFirstSuccessfulResolver(t *testing.T) {
+	want := container.NewID()
+	unreached := &fakeResolver{id: container.NewID()}
+	chain := NewChain(
+		&fakeResolver{err: errors.New("nns: not found")},
+		&fakeResolver{id: want},
+		unreached,
+	)
+
+	got, err := chain.Resolve(context.Background(), "example")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("resolved %p, want the id returned by the first successful resolver (%p)", got, want)
+	}
+}
+
+func TestChainReturnsLastErrorWhenAllResolversFail(t *testing.T) {
+	errA := errors.New("nns: not found")
+	errB := errors.New("dns: not found")
+	chain := NewChain(&fakeResolver{err: errA}, &fakeResolver{err: errB})
+
+	_, err := chain.Resolve(context.Background(), "example")
+	if !errors.Is(err, errB) {
+		t.Fatalf("expected %v, got %v", errB, err)
+	}
+}
+
+func TestChainWithNoResolvers(t *testing.T) {
+	chain := NewChain()
+	if _, err := chain.Resolve(context.Background(), "example"); err == nil {
+		t.Fatal("expected an error when no resolvers are configured")
+	}
+}
@@ -0,0 +1,26 @@
+package uploader
+
+import (
+	"fmt"
+	"io"
+)
+
+// countingReader wraps an io.Reader and tracks how many bytes have been read
+// through it, so payload size can be measured as it streams through rather
+// than by buffering it up front. If limit is positive, Read starts failing
+// once more than limit bytes have been read, enforcing a per-object size cap
+// even for parts that never declare a Content-Length.
+type countingReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	if cr.limit > 0 && cr.n > cr.limit {
+		return n, fmt.Errorf("payload exceeds max object size hint of %d bytes", cr.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,119 @@
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/object"
+)
+
+// attributeExpirationEpoch is the well-known object attribute NeoFS nodes
+// use to garbage-collect an object once the given epoch is reached.
+const attributeExpirationEpoch = "__NEOFS__EXPIRATION_EPOCH"
+
+// Request headers accepted on the upload path to express object lifetime in
+// a more convenient form than a raw epoch number. When several are set, the
+// most specific one wins (Epoch, then Timestamp or RFC3339, then Duration)
+// and the others are ignored without comparing their values; setting both
+// Timestamp and RFC3339 is rejected.
+const (
+	hdrExpirationEpoch     = "Neofs-Expiration-Epoch"
+	hdrExpirationDuration  = "Neofs-Expiration-Duration"
+	hdrExpirationTimestamp = "Neofs-Expiration-Timestamp"
+	hdrExpirationRFC3339   = "Neofs-Expiration-RFC3339"
+)
+
+// expirationEpochAttribute inspects filtered (the already-collected
+// X-Attribute-* headers with their "X-Attribute-" prefix stripped) for the
+// expiration headers, removes them from filtered so they aren't attached to
+// the object verbatim, and returns the __NEOFS__EXPIRATION_EPOCH attribute
+// to use instead. It returns (nil, nil) if no expiration header was set.
+func (u *Uploader) expirationEpochAttribute(ctx context.Context, filtered map[string]string) (*object.Attribute, error) {
+	epochStr, hasEpoch := filtered[hdrExpirationEpoch]
+	durStr, hasDuration := filtered[hdrExpirationDuration]
+	tsStr, hasTimestamp := filtered[hdrExpirationTimestamp]
+	rfcStr, hasRFC3339 := filtered[hdrExpirationRFC3339]
+
+	if !hasEpoch && !hasDuration && !hasTimestamp && !hasRFC3339 {
+		return nil, nil
+	}
+	delete(filtered, hdrExpirationEpoch)
+	delete(filtered, hdrExpirationDuration)
+	delete(filtered, hdrExpirationTimestamp)
+	delete(filtered, hdrExpirationRFC3339)
+
+	if hasTimestamp && hasRFC3339 {
+		return nil, fmt.Errorf("conflicting expiration headers: both %q and %q set", hdrExpirationTimestamp, hdrExpirationRFC3339)
+	}
+
+	var epoch uint64
+	var err error
+	switch {
+	case hasEpoch:
+		if epoch, err = strconv.ParseUint(epochStr, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", hdrExpirationEpoch, err)
+		}
+	case hasTimestamp, hasRFC3339:
+		var t time.Time
+		if hasRFC3339 {
+			t, err = time.Parse(time.RFC3339, rfcStr)
+		} else {
+			t, err = parseExpirationTimestamp(tsStr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiration timestamp: %w", err)
+		}
+		if !t.After(time.Now()) {
+			return nil, fmt.Errorf("expiration timestamp is in the past")
+		}
+		if epoch, err = u.epochAt(ctx, t); err != nil {
+			return nil, err
+		}
+	case hasDuration:
+		d, derr := time.ParseDuration(durStr)
+		if derr != nil {
+			return nil, fmt.Errorf("invalid %s: %w", hdrExpirationDuration, derr)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("%s must be positive", hdrExpirationDuration)
+		}
+		if epoch, err = u.epochAt(ctx, time.Now().Add(d)); err != nil {
+			return nil, err
+		}
+	}
+
+	attr := object.NewAttribute()
+	attr.SetKey(attributeExpirationEpoch)
+	attr.SetValue(strconv.FormatUint(epoch, 10))
+	return attr, nil
+}
+
+// parseExpirationTimestamp accepts either a unix timestamp (seconds) or an
+// RFC3339 timestamp.
+func parseExpirationTimestamp(s string) (time.Time, error) {
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// epochAt converts t into a target epoch using the cached netmap info:
+// targetEpoch = currentEpoch + ceil(remaining / (msPerBlock * epochDuration)).
+func (u *Uploader) epochAt(ctx context.Context, t time.Time) (uint64, error) {
+	info, err := u.netmapCache.get(ctx, u.pool)
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch netmap info: %w", err)
+	}
+	if info.msPerBlock == 0 || info.epochDuration == 0 {
+		return 0, fmt.Errorf("netmap epoch duration is unknown")
+	}
+	remaining := time.Until(t).Milliseconds()
+	if remaining <= 0 {
+		return info.currentEpoch, nil
+	}
+	epochMs := info.msPerBlock * info.epochDuration
+	return info.currentEpoch + uint64(math.Ceil(float64(remaining)/float64(epochMs))), nil
+}
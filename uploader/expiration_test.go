@@ -0,0 +1,81 @@
+package uploader
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newTestUploaderWithNetmap returns an Uploader whose netmap cache is
+// pre-seeded with info, so expirationEpochAttribute/epochAt resolve
+// deterministically without ever touching u.pool. The netmap fetch path
+// itself (fetchNetmapInfo/networkInfo/netmapCache.get) is covered separately
+// in netmap_test.go.
+func newTestUploaderWithNetmap(info netmapInfo) *Uploader {
+	return &Uploader{
+		settings: NewSettings(false, 0, 0, 0),
+		netmapCache: &netmapCache{
+			ttl:     time.Hour,
+			fetched: time.Now(),
+			info:    info,
+		},
+	}
+}
+
+func TestExpirationEpochAttributeFromEpochHeader(t *testing.T) {
+	u := newTestUploaderWithNetmap(netmapInfo{currentEpoch: 10, msPerBlock: 1000, epochDuration: 100})
+	filtered := map[string]string{hdrExpirationEpoch: "42"}
+
+	attr, err := u.expirationEpochAttribute(context.Background(), filtered)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attr == nil || attr.Value() != "42" {
+		t.Fatalf("expected epoch attribute 42, got %+v", attr)
+	}
+	if _, ok := filtered[hdrExpirationEpoch]; ok {
+		t.Fatal("expected the raw header to be removed from filtered")
+	}
+}
+
+func TestExpirationEpochAttributeFromDuration(t *testing.T) {
+	// epoch duration = msPerBlock * epochDuration = 1000ms * 100 = 100s.
+	u := newTestUploaderWithNetmap(netmapInfo{currentEpoch: 5, msPerBlock: 1000, epochDuration: 100})
+	filtered := map[string]string{hdrExpirationDuration: "150s"}
+
+	attr, err := u.expirationEpochAttribute(context.Background(), filtered)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// ceil(150s / 100s) = 2 epochs past the current one.
+	if attr.Value() != "7" {
+		t.Fatalf("expected epoch 7, got %s", attr.Value())
+	}
+}
+
+func TestExpirationEpochAttributeConflictingHeaders(t *testing.T) {
+	u := newTestUploaderWithNetmap(netmapInfo{currentEpoch: 1, msPerBlock: 1, epochDuration: 1})
+	filtered := map[string]string{
+		hdrExpirationTimestamp: "4102444800", // 2100-01-01
+		hdrExpirationRFC3339:   "2200-01-01T00:00:00Z",
+	}
+	if _, err := u.expirationEpochAttribute(context.Background(), filtered); err == nil {
+		t.Fatal("expected an error for conflicting expiration headers")
+	}
+}
+
+func TestExpirationEpochAttributeRejectsPastTimestamp(t *testing.T) {
+	u := newTestUploaderWithNetmap(netmapInfo{currentEpoch: 1, msPerBlock: 1, epochDuration: 1})
+	filtered := map[string]string{hdrExpirationTimestamp: "946684800"} // 2000-01-01
+	if _, err := u.expirationEpochAttribute(context.Background(), filtered); err == nil {
+		t.Fatal("expected an error for a past expiration timestamp")
+	}
+}
+
+func TestExpirationEpochAttributeNoHeadersIsNoop(t *testing.T) {
+	u := newTestUploaderWithNetmap(netmapInfo{})
+	attr, err := u.expirationEpochAttribute(context.Background(), map[string]string{})
+	if err != nil || attr != nil {
+		t.Fatalf("expected (nil, nil), got (%v, %v)", attr, err)
+	}
+}
@@ -0,0 +1,123 @@
+package uploader
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/object"
+	"go.uber.org/zap"
+)
+
+const (
+	attributeContentType = "Content-Type"
+	partXAttributePrefix = "X-Attribute-"
+)
+
+// MultipartFile is a single part of a multipart/form-data upload request,
+// readable as an object payload.
+type MultipartFile interface {
+	io.Reader
+	io.Closer
+	FileName() string
+}
+
+// multipartReader iterates over the parts of a multipart/form-data request
+// body, handing each one out as a MultipartFile. Unlike a one-shot fetch, it
+// can be drained by repeatedly calling NextFile until it returns io.EOF.
+type multipartReader struct {
+	mr   *multipart.Reader
+	body io.Reader
+}
+
+// newMultipartReader returns a reader over the parts of body, delimited by
+// boundary.
+func newMultipartReader(body io.Reader, boundary string) (*multipartReader, error) {
+	if boundary == "" {
+		return nil, fmt.Errorf("missing multipart boundary")
+	}
+	return &multipartReader{mr: multipart.NewReader(body, boundary), body: body}, nil
+}
+
+// NextFile returns the next part of the request as a MultipartFile. It
+// returns io.EOF once every part has been consumed, draining any trailing
+// bytes of the request body in the process.
+func (r *multipartReader) NextFile() (*multipartFile, error) {
+	part, err := r.mr.NextPart()
+	if err == io.EOF {
+		_, _ = io.Copy(io.Discard, r.body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &multipartFile{part: part}, nil
+}
+
+type multipartFile struct {
+	part *multipart.Part
+}
+
+func (f *multipartFile) Read(p []byte) (int, error) { return f.part.Read(p) }
+
+func (f *multipartFile) Close() error { return f.part.Close() }
+
+func (f *multipartFile) FileName() string {
+	if name := f.part.FileName(); name != "" {
+		return name
+	}
+	return f.part.FormName()
+}
+
+// Header returns the MIME header of the underlying part, so per-part
+// Content-Type and X-Attribute-* fields can be turned into object
+// attributes.
+func (f *multipartFile) Header() textproto.MIMEHeader {
+	return f.part.Header
+}
+
+// partAttributes builds the attribute set for a single part: its own
+// X-Attribute-* headers and Content-Type take priority, falling back to the
+// request-level ones in reqFiltered, then to FileName if nothing set it.
+func partAttributes(log *zap.Logger, file *multipartFile, reqFiltered map[string]string) []*object.Attribute {
+	merged := make(map[string]string, len(reqFiltered))
+	for k, v := range reqFiltered {
+		merged[k] = v
+	}
+	for k, v := range filterMIMEHeader(log, file.Header()) {
+		merged[k] = v
+	}
+
+	attrs := make([]*object.Attribute, 0, len(merged)+1)
+	for k, v := range merged {
+		attr := object.NewAttribute()
+		attr.SetKey(k)
+		attr.SetValue(v)
+		attrs = append(attrs, attr)
+	}
+	if _, ok := merged[object.AttributeFileName]; !ok {
+		attr := object.NewAttribute()
+		attr.SetKey(object.AttributeFileName)
+		attr.SetValue(file.FileName())
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+// filterMIMEHeader extracts object attributes out of a multipart part's MIME
+// header: its Content-Type and any X-Attribute-* fields, mirroring how
+// filterHeaders treats the request-level headers.
+func filterMIMEHeader(log *zap.Logger, header textproto.MIMEHeader) map[string]string {
+	filtered := make(map[string]string)
+	if ct := header.Get(attributeContentType); ct != "" {
+		filtered[attributeContentType] = ct
+	}
+	for key := range header {
+		if !strings.HasPrefix(key, partXAttributePrefix) {
+			continue
+		}
+		filtered[strings.TrimPrefix(key, partXAttributePrefix)] = header.Get(key)
+	}
+	return filtered
+}
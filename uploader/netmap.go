@@ -0,0 +1,76 @@
+package uploader
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/client"
+	"github.com/nspcc-dev/neofs-sdk-go/pkg/pool"
+)
+
+const networkParamEpochDuration = "EpochDuration"
+
+// netmapInfo holds just enough of the current NeoFS network state to
+// translate a wall-clock duration or timestamp into a target epoch.
+type netmapInfo struct {
+	currentEpoch  uint64
+	msPerBlock    uint64
+	epochDuration uint64
+}
+
+// netmapCache caches netmapInfo for a TTL so that converting an expiration
+// header into an epoch doesn't cost a network round-trip on every upload.
+type netmapCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	fetched time.Time
+	info    netmapInfo
+}
+
+func newNetmapCache(ttl time.Duration) *netmapCache {
+	return &netmapCache{ttl: ttl}
+}
+
+func (c *netmapCache) get(ctx context.Context, conns pool.Pool) (netmapInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ttl > 0 && time.Since(c.fetched) < c.ttl {
+		return c.info, nil
+	}
+	info, err := fetchNetmapInfo(ctx, conns)
+	if err != nil {
+		return netmapInfo{}, err
+	}
+	c.info, c.fetched = info, time.Now()
+	return c.info, nil
+}
+
+func fetchNetmapInfo(ctx context.Context, conns pool.Pool) (netmapInfo, error) {
+	conn, _, err := conns.Connection()
+	if err != nil {
+		return netmapInfo{}, fmt.Errorf("could not get neofs connection: %w", err)
+	}
+	return networkInfo(ctx, conn)
+}
+
+func networkInfo(ctx context.Context, conn client.Client) (netmapInfo, error) {
+	ni, err := conn.NetworkInfo(ctx)
+	if err != nil {
+		return netmapInfo{}, fmt.Errorf("could not fetch network info: %w", err)
+	}
+	var epochDuration uint64
+	for _, p := range ni.RawNetworkParameters() {
+		if p.Key() == networkParamEpochDuration && len(p.Value()) == 8 {
+			epochDuration = binary.LittleEndian.Uint64(p.Value())
+		}
+	}
+	return netmapInfo{
+		currentEpoch:  ni.CurrentEpoch(),
+		msPerBlock:    uint64(ni.MsPerBlock()),
+		epochDuration: epochDuration,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package uploader
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/netmap"
+)
+
+// countingNetworkInfoClient wraps fakeClient and counts how many times
+// NetworkInfo was actually invoked, so a cache hit can be told apart from a
+// fetch.
+type countingNetworkInfoClient struct {
+	fakeClient
+	calls int
+}
+
+func (c *countingNetworkInfoClient) NetworkInfo(ctx context.Context) (*netmap.NetworkInfo, error) {
+	c.calls++
+	return c.fakeClient.NetworkInfo(ctx)
+}
+
+// buildTestNetworkInfo constructs a *netmap.NetworkInfo carrying a single
+// EpochDuration raw parameter, mirroring what a real NeoFS node returns over
+// the wire.
+func buildTestNetworkInfo(currentEpoch uint64, msPerBlock int64, epochDuration uint64) *netmap.NetworkInfo {
+	value := make([]byte, 8)
+	binary.LittleEndian.PutUint64(value, epochDuration)
+
+	param := netmap.NewNetworkParameter()
+	param.SetKey(networkParamEpochDuration)
+	param.SetValue(value)
+
+	ni := netmap.NewNetworkInfo()
+	ni.SetCurrentEpoch(currentEpoch)
+	ni.SetMsPerBlock(msPerBlock)
+	ni.SetRawNetworkParameters(param)
+	return ni
+}
+
+func TestNetworkInfoDecodesEpochDurationParameter(t *testing.T) {
+	conn := &fakeClient{netInfo: buildTestNetworkInfo(42, 1000, 100)}
+
+	got, err := networkInfo(context.Background(), conn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := netmapInfo{currentEpoch: 42, msPerBlock: 1000, epochDuration: 100}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNetworkInfoIgnoresMalformedEpochDurationValue(t *testing.T) {
+	param := netmap.NewNetworkParameter()
+	param.SetKey(networkParamEpochDuration)
+	param.SetValue([]byte{1, 2, 3}) // not the expected 8 bytes
+
+	ni := netmap.NewNetworkInfo()
+	ni.SetCurrentEpoch(1)
+	ni.SetMsPerBlock(1)
+	ni.SetRawNetworkParameters(param)
+
+	got, err := networkInfo(context.Background(), &fakeClient{netInfo: ni})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.epochDuration != 0 {
+		t.Fatalf("expected a malformed parameter to be ignored, got epochDuration=%d", got.epochDuration)
+	}
+}
+
+func TestFetchNetmapInfoUsesPoolConnection(t *testing.T) {
+	p := &fakePool{conn: &fakeClient{netInfo: buildTestNetworkInfo(7, 2000, 50)}}
+
+	got, err := fetchNetmapInfo(context.Background(), p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := netmapInfo{currentEpoch: 7, msPerBlock: 2000, epochDuration: 50}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestNetmapCacheGetFetchesOnceWithinTTL(t *testing.T) {
+	conn := &countingNetworkInfoClient{fakeClient: fakeClient{netInfo: buildTestNetworkInfo(1, 1, 1)}}
+	p := &fakePool{conn: conn}
+	cache := newNetmapCache(time.Hour)
+
+	if _, err := cache.get(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cache.get(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conn.calls != 1 {
+		t.Fatalf("expected a single network round-trip within the TTL, got %d", conn.calls)
+	}
+}
+
+func TestNetmapCacheGetRefetchesAfterTTLExpires(t *testing.T) {
+	conn := &countingNetworkInfoClient{fakeClient: fakeClient{netInfo: buildTestNetworkInfo(1, 1, 1)}}
+	p := &fakePool{conn: conn}
+	cache := newNetmapCache(time.Nanosecond)
+
+	if _, err := cache.get(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := cache.get(context.Background(), p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if conn.calls != 2 {
+		t.Fatalf("expected the cache to refetch after its TTL expired, got %d calls", conn.calls)
+	}
+}
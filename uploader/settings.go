@@ -0,0 +1,60 @@
+package uploader
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Settings holds the upload knobs that can be changed at runtime (e.g. on
+// SIGHUP, by re-reading viper config) without restarting the fasthttp server
+// or recreating the pool. Upload reads every value atomically per-request.
+type Settings struct {
+	defaultTimestamp          atomic.Bool
+	maxObjectSizeHint         atomic.Int64
+	maxParts                  atomic.Int64
+	defaultExpirationDuration atomic.Int64 // time.Duration, nanoseconds
+}
+
+// NewSettings returns Settings initialised with the given defaults.
+func NewSettings(defaultTimestamp bool, maxObjectSizeHint int64, maxParts int, defaultExpirationDuration time.Duration) *Settings {
+	s := new(Settings)
+	s.SetDefaultTimestamp(defaultTimestamp)
+	s.SetMaxObjectSizeHint(maxObjectSizeHint)
+	s.SetMaxParts(maxParts)
+	s.SetDefaultExpirationDuration(defaultExpirationDuration)
+	return s
+}
+
+// DefaultTimestamp reports whether a Timestamp attribute should be added to
+// objects that don't carry one already.
+func (s *Settings) DefaultTimestamp() bool { return s.defaultTimestamp.Load() }
+
+// SetDefaultTimestamp updates DefaultTimestamp.
+func (s *Settings) SetDefaultTimestamp(v bool) { s.defaultTimestamp.Store(v) }
+
+// MaxObjectSizeHint returns the payload size above which Upload aborts a
+// single part's object, or zero if unset. It is enforced per part, not
+// against the combined size of a multipart request.
+func (s *Settings) MaxObjectSizeHint() int64 { return s.maxObjectSizeHint.Load() }
+
+// SetMaxObjectSizeHint updates MaxObjectSizeHint.
+func (s *Settings) SetMaxObjectSizeHint(v int64) { s.maxObjectSizeHint.Store(v) }
+
+// MaxParts returns how many parts a single multipart upload request may
+// contain, or zero if unlimited.
+func (s *Settings) MaxParts() int { return int(s.maxParts.Load()) }
+
+// SetMaxParts updates MaxParts.
+func (s *Settings) SetMaxParts(v int) { s.maxParts.Store(int64(v)) }
+
+// DefaultExpirationDuration returns the object lifetime applied when the
+// upload request carries no expiration header, or zero to leave objects
+// unbounded.
+func (s *Settings) DefaultExpirationDuration() time.Duration {
+	return time.Duration(s.defaultExpirationDuration.Load())
+}
+
+// SetDefaultExpirationDuration updates DefaultExpirationDuration.
+func (s *Settings) SetDefaultExpirationDuration(v time.Duration) {
+	s.defaultExpirationDuration.Store(int64(v))
+}
@@ -0,0 +1,51 @@
+package uploader
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSettingsConcurrentAccess flips every Settings field from one goroutine
+// while several others read it as Upload would on every request. Run with
+// -race: atomic.Bool/atomic.Int64 make this safe, but a regression back to a
+// plain field would be caught immediately.
+func TestSettingsConcurrentAccess(t *testing.T) {
+	s := NewSettings(true, 1024, 4, time.Hour)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.SetDefaultTimestamp(i%2 == 0)
+			s.SetMaxObjectSizeHint(int64(i))
+			s.SetMaxParts(i % 10)
+			s.SetDefaultExpirationDuration(time.Duration(i) * time.Second)
+		}
+	}()
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_ = s.DefaultTimestamp()
+				_ = s.MaxObjectSizeHint()
+				_ = s.MaxParts()
+				_ = s.DefaultExpirationDuration()
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+}
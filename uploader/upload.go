@@ -3,6 +3,7 @@ package uploader
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"strconv"
 	"time"
@@ -12,100 +13,128 @@ import (
 	"github.com/nspcc-dev/neofs-api-go/pkg/object"
 	"github.com/nspcc-dev/neofs-api-go/pkg/owner"
 	"github.com/nspcc-dev/neofs-api-go/pkg/token"
+	"github.com/nspcc-dev/neofs-http-gw/metrics"
+	"github.com/nspcc-dev/neofs-http-gw/resolver"
 	"github.com/nspcc-dev/neofs-http-gw/tokens"
 	"github.com/nspcc-dev/neofs-sdk-go/pkg/pool"
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
 )
 
-const (
-	jsonHeader   = "application/json; charset=UTF-8"
-	drainBufSize = 4096
-)
+const jsonHeader = "application/json; charset=UTF-8"
 
 // Uploader is an upload request handler.
 type Uploader struct {
-	log                    *zap.Logger
-	pool                   pool.Pool
-	enableDefaultTimestamp bool
+	appCtx         context.Context
+	log            *zap.Logger
+	pool           pool.Pool
+	settings       *Settings
+	resolver       resolver.ContainerResolver
+	netmapCache    *netmapCache
+	metrics        *metrics.Collector
+	requestTimeout time.Duration
 }
 
 // New creates a new Uploader using specified logger, connection pool and
-// other options.
-func New(log *zap.Logger, conns pool.Pool, enableDefaultTimestamp bool) *Uploader {
-	return &Uploader{log, conns, enableDefaultTimestamp}
+// other options. appCtx is the application's root context: each NeoFS PUT
+// runs under context.WithTimeout(appCtx, requestTimeout) rather than the
+// fasthttp RequestCtx, so cancelling appCtx on shutdown cleanly aborts any
+// in-flight PUTs and a slow client can't pin a NeoFS call indefinitely. The
+// resolver may be nil, in which case the {cid} path parameter must always be
+// a raw container ID. netmapCacheTTL governs how long netmap info fetched to
+// resolve expiration headers is reused. settings is shared with the caller
+// so it can be updated at runtime (e.g. on SIGHUP) and have Upload observe
+// the new values immediately. collector may be nil, in which case Upload
+// simply isn't instrumented.
+func New(appCtx context.Context, log *zap.Logger, conns pool.Pool, settings *Settings, cr resolver.ContainerResolver, netmapCacheTTL time.Duration, collector *metrics.Collector, requestTimeout time.Duration) *Uploader {
+	return &Uploader{appCtx, log, conns, settings, cr, newNetmapCache(netmapCacheTTL), collector, requestTimeout}
+}
+
+// uploadResult is the per-part outcome reported back for a multipart upload
+// request.
+type uploadResult struct {
+	ObjectID    string `json:"object_id,omitempty"`
+	ContainerID string `json:"container_id,omitempty"`
+	FileName    string `json:"filename"`
+	Error       string `json:"error,omitempty"`
 }
 
-// Upload handles multipart upload request.
+// Upload handles a multipart upload request. Every part of the request is
+// PUT into NeoFS as a separate object; the response is a JSON array with one
+// uploadResult per part.
 func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 	var (
 		err        error
-		file       MultipartFile
-		obj        *object.ID
+		reader     *multipartReader
 		conn       client.Client
 		tkn        *token.SessionToken
-		addr       = object.NewAddress()
 		cid        = container.NewID()
 		scid, _    = c.UserValue("cid").(string)
 		log        = u.log.With(zap.String("cid", scid))
 		bodyStream = c.RequestBodyStream()
-		drainBuf   = make([]byte, drainBufSize)
 	)
+	u.metrics.IncInFlight()
+	defer u.metrics.DecInFlight()
+	defer func() { u.metrics.ObserveRequest(cid.String(), c.Response.StatusCode()) }()
+	// NeoFS calls other than the per-part PUTs (container name resolution,
+	// netmap lookups for expiration) share this context so they too are tied
+	// to the application's lifetime rather than the HTTP connection's.
+	reqCtx, cancel := context.WithTimeout(u.appCtx, u.requestTimeout)
+	defer cancel()
 	if err = tokens.StoreBearerToken(c); err != nil {
 		log.Error("could not fetch bearer token", zap.Error(err))
 		c.Error("could not fetch bearer token", fasthttp.StatusBadRequest)
 		return
 	}
 	if err = cid.Parse(scid); err != nil {
-		log.Error("wrong container id", zap.Error(err))
-		c.Error("wrong container id", fasthttp.StatusBadRequest)
-		return
-	}
-	defer func() {
-		// If the temporary reader can be closed - let's close it.
-		if file == nil {
+		if u.resolver == nil {
+			log.Error("wrong container id", zap.Error(err))
+			c.Error("wrong container id", fasthttp.StatusBadRequest)
 			return
 		}
-		err := file.Close()
-		log.Debug(
-			"close temporary multipart/form file",
-			zap.Stringer("address", addr),
-			zap.String("filename", file.FileName()),
-			zap.Error(err),
-		)
-	}()
+		resolved, rerr := u.resolver.Resolve(reqCtx, scid)
+		if rerr != nil {
+			log.Error("wrong container id", zap.Error(err), zap.Error(rerr))
+			c.Error("wrong container id", fasthttp.StatusBadRequest)
+			return
+		}
+		cid = resolved
+	}
 	boundary := string(c.Request.Header.MultipartFormBoundary())
-	if file, err = fetchMultipartFile(u.log, bodyStream, boundary); err != nil {
+	if reader, err = newMultipartReader(bodyStream, boundary); err != nil {
 		log.Error("could not receive multipart/form", zap.Error(err))
 		c.Error("could not receive multipart/form: "+err.Error(), fasthttp.StatusBadRequest)
 		return
 	}
-	filtered := filterHeaders(u.log, &c.Request.Header)
-	attributes := make([]*object.Attribute, 0, len(filtered))
-	// prepares attributes from filtered headers
-	for key, val := range filtered {
-		attribute := object.NewAttribute()
-		attribute.SetKey(key)
-		attribute.SetValue(val)
-		attributes = append(attributes, attribute)
-	}
-	// sets FileName attribute if it wasn't set from header
-	if _, ok := filtered[object.AttributeFileName]; !ok {
-		filename := object.NewAttribute()
-		filename.SetKey(object.AttributeFileName)
-		filename.SetValue(file.FileName())
-		attributes = append(attributes, filename)
+	reqFiltered := filterHeaders(u.log, &c.Request.Header)
+	// normalises any X-Attribute-Neofs-Expiration-* header into a single
+	// __NEOFS__EXPIRATION_EPOCH attribute shared by every part
+	expAttr, err := u.expirationEpochAttribute(reqCtx, reqFiltered)
+	if err != nil {
+		log.Error("invalid expiration header", zap.Error(err))
+		c.Error("invalid expiration header: "+err.Error(), fasthttp.StatusBadRequest)
+		return
 	}
 	// sets Timestamp attribute if it wasn't set from header and enabled by settings
-	if _, ok := filtered[object.AttributeTimestamp]; !ok && u.enableDefaultTimestamp {
-		timestamp := object.NewAttribute()
-		timestamp.SetKey(object.AttributeTimestamp)
-		timestamp.SetValue(strconv.FormatInt(time.Now().Unix(), 10))
-		attributes = append(attributes, timestamp)
+	if _, ok := reqFiltered[object.AttributeTimestamp]; !ok && u.settings.DefaultTimestamp() {
+		reqFiltered[object.AttributeTimestamp] = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	// falls back to the configured default object lifetime if nothing set one explicitly
+	if expAttr == nil {
+		if d := u.settings.DefaultExpirationDuration(); d > 0 {
+			epoch, eerr := u.epochAt(reqCtx, time.Now().Add(d))
+			if eerr != nil {
+				log.Error("could not apply default expiration duration", zap.Error(eerr))
+				c.Error("could not apply default expiration duration", fasthttp.StatusInternalServerError)
+				return
+			}
+			expAttr = object.NewAttribute()
+			expAttr.SetKey(attributeExpirationEpoch)
+			expAttr.SetValue(strconv.FormatUint(epoch, 10))
+		}
 	}
 	oid, bt := u.fetchOwnerAndBearerToken(c)
 
-	// Try to put file into NeoFS or throw an error.
 	conn, tkn, err = u.pool.Connection()
 	if err != nil {
 		log.Error("failed to get neofs connection artifacts", zap.Error(err))
@@ -113,44 +142,93 @@ func (u *Uploader) Upload(c *fasthttp.RequestCtx) {
 		return
 	}
 
-	rawObject := object.NewRaw()
-	rawObject.SetContainerID(cid)
-	rawObject.SetOwnerID(oid)
-	rawObject.SetAttributes(attributes...)
+	var (
+		results []uploadResult
+		anyOK   bool
+		allOK   = true
+	)
+	for i := 0; ; i++ {
+		file, ferr := reader.NextFile()
+		if ferr == io.EOF {
+			break
+		}
+		if ferr != nil {
+			u.metrics.IncMultipartError()
+			log.Error("could not read multipart/form part", zap.Error(ferr))
+			c.Error("could not read multipart/form: "+ferr.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+		if maxParts := u.settings.MaxParts(); maxParts > 0 && i >= maxParts {
+			_ = file.Close()
+			log.Error("too many parts in multipart/form request", zap.Int("max_parts", maxParts))
+			// Parts 0..i-1 are already PUT into NeoFS; their object IDs must
+			// still reach the client, so the rejected part is reported as a
+			// trailing error entry in the same response rather than
+			// discarding results in favour of a contentless 413.
+			results = append(results, uploadResult{
+				FileName: file.FileName(),
+				Error:    fmt.Sprintf("too many parts in multipart/form request, max_parts=%d", maxParts),
+			})
+			allOK = false
+			break
+		}
 
-	ops := new(client.PutObjectParams).WithObject(rawObject.Object()).WithPayloadReader(file)
+		res := uploadResult{FileName: file.FileName()}
+		attributes := partAttributes(u.log, file, reqFiltered)
+		if expAttr != nil {
+			attributes = append(attributes, expAttr)
+		}
 
-	if obj, err = conn.PutObject(c, ops, client.WithSession(tkn), client.WithBearer(bt)); err != nil {
-		log.Error("could not store file in neofs", zap.Error(err))
-		c.Error("could not store file in neofs", fasthttp.StatusBadRequest)
-		return
+		rawObject := object.NewRaw()
+		rawObject.SetContainerID(cid)
+		rawObject.SetOwnerID(oid)
+		rawObject.SetAttributes(attributes...)
+
+		payload := &countingReader{r: file, limit: u.settings.MaxObjectSizeHint()}
+		ops := new(client.PutObjectParams).WithObject(rawObject.Object()).WithPayloadReader(payload)
+		partStart := time.Now()
+		putCtx, cancel := context.WithTimeout(u.appCtx, u.requestTimeout)
+		obj, perr := conn.PutObject(putCtx, ops, client.WithSession(tkn), client.WithBearer(bt))
+		cancel()
+
+		closeErr := file.Close()
+		log.Debug("close temporary multipart/form file",
+			zap.String("filename", res.FileName), zap.Error(closeErr))
+
+		if perr != nil {
+			u.metrics.IncPutError()
+			log.Error("could not store file in neofs", zap.String("filename", res.FileName), zap.Error(perr))
+			res.Error = perr.Error()
+			allOK = false
+		} else {
+			addr := object.NewAddress()
+			addr.SetObjectID(obj)
+			addr.SetContainerID(cid)
+			res.ObjectID = addr.ObjectID().String()
+			res.ContainerID = addr.ContainerID().String()
+			anyOK = true
+		}
+		u.metrics.ObservePartUpload(time.Since(partStart), payload.n)
+		results = append(results, res)
 	}
-
-	addr.SetObjectID(obj)
-	addr.SetContainerID(cid)
-
-	// Try to return the response, otherwise, if something went wrong, throw an error.
-	if err = newPutResponse(addr).encode(c); err != nil {
-		log.Error("could not prepare response", zap.Error(err))
-		c.Error("could not prepare response", fasthttp.StatusBadRequest)
-
+	if len(results) == 0 {
+		log.Error("no parts found in multipart/form request")
+		c.Error("no parts found in multipart/form request", fasthttp.StatusBadRequest)
 		return
 	}
-	// Multipart is multipart and thus can contain more than one part which
-	// we ignore at the moment. Also, when dealing with chunked encoding
-	// the last zero-length chunk might be left unread (because multipart
-	// reader only cares about its boundary and doesn't look further) and
-	// it will be (erroneously) interpreted as the start of the next
-	// pipelined header. Thus we need to drain the body buffer.
-	for {
-		_, err = bodyStream.Read(drainBuf)
-		if err == io.EOF || err == io.ErrUnexpectedEOF {
-			break
-		}
+
+	status := fasthttp.StatusOK
+	switch {
+	case !allOK && anyOK:
+		status = fasthttp.StatusMultiStatus
+	case !allOK:
+		status = fasthttp.StatusBadGateway
 	}
-	// Report status code and content type.
-	c.Response.SetStatusCode(fasthttp.StatusOK)
+	c.Response.SetStatusCode(status)
 	c.Response.Header.SetContentType(jsonHeader)
+	if err = json.NewEncoder(c).Encode(results); err != nil {
+		log.Error("could not prepare response", zap.Error(err))
+	}
 }
 
 func (u *Uploader) fetchOwnerAndBearerToken(ctx context.Context) (*owner.ID, *token.BearerToken) {
@@ -159,21 +237,3 @@ func (u *Uploader) fetchOwnerAndBearerToken(ctx context.Context) (*owner.ID, *to
 	}
 	return u.pool.OwnerID(), nil
 }
-
-type putResponse struct {
-	ObjectID    string `json:"object_id"`
-	ContainerID string `json:"container_id"`
-}
-
-func newPutResponse(addr *object.Address) *putResponse {
-	return &putResponse{
-		ObjectID:    addr.ObjectID().String(),
-		ContainerID: addr.ContainerID().String(),
-	}
-}
-
-func (pr *putResponse) encode(w io.Writer) error {
-	enc := json.NewEncoder(w)
-	enc.SetIndent("", "\t")
-	return enc.Encode(pr)
-}
@@ -0,0 +1,140 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-api-go/pkg/client"
+	"github.com/nspcc-dev/neofs-api-go/pkg/container"
+	"github.com/nspcc-dev/neofs-api-go/pkg/netmap"
+	"github.com/nspcc-dev/neofs-api-go/pkg/object"
+	"github.com/nspcc-dev/neofs-api-go/pkg/owner"
+	"github.com/nspcc-dev/neofs-api-go/pkg/token"
+	"github.com/nspcc-dev/neofs-sdk-go/pkg/pool"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/zap"
+)
+
+// fakeClient embeds client.Client so it satisfies the full interface; only
+// the methods Upload actually calls are overridden, every other method
+// panics if exercised.
+type fakeClient struct {
+	client.Client
+	block      bool
+	netInfo    *netmap.NetworkInfo
+	netInfoErr error
+}
+
+func (c *fakeClient) PutObject(ctx context.Context, _ *client.PutObjectParams, _ ...client.CallOption) (*object.ID, error) {
+	if c.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return object.NewID(), nil
+}
+
+func (c *fakeClient) NetworkInfo(context.Context) (*netmap.NetworkInfo, error) {
+	return c.netInfo, c.netInfoErr
+}
+
+// fakePool embeds pool.Pool so it satisfies the full interface; only
+// Connection and OwnerID are overridden.
+type fakePool struct {
+	pool.Pool
+	conn client.Client
+}
+
+func (p *fakePool) Connection() (client.Client, *token.SessionToken, error) {
+	return p.conn, nil, nil
+}
+
+func (p *fakePool) OwnerID() *owner.ID { return nil }
+
+// fakeContainerResolver always resolves to id, so tests can exercise Upload
+// without needing a container ID string that parses as raw NeoFS container
+// ID.
+type fakeContainerResolver struct {
+	id *container.ID
+}
+
+func (r *fakeContainerResolver) Resolve(context.Context, string) (*container.ID, error) {
+	return r.id, nil
+}
+
+// multipartBody builds a single-part multipart/form-data body and returns it
+// together with its boundary.
+func multipartBody(t *testing.T, filename string, content []byte) ([]byte, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("could not create form file: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("could not write part content: %v", err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("could not close multipart writer: %v", err)
+	}
+	return buf.Bytes(), mw.Boundary()
+}
+
+// newUploadRequestCtx returns a *fasthttp.RequestCtx carrying a single-part
+// multipart/form-data request for cid, as Upload expects to receive it from
+// the router.
+func newUploadRequestCtx(t *testing.T, cid string) *fasthttp.RequestCtx {
+	t.Helper()
+	body, boundary := multipartBody(t, "test.txt", []byte("hello"))
+
+	c := &fasthttp.RequestCtx{}
+	c.Request.Header.SetMethod(fasthttp.MethodPost)
+	c.Request.Header.SetContentType("multipart/form-data; boundary=" + boundary)
+	c.Request.SetBodyStream(bytes.NewReader(body), len(body))
+	c.SetUserValue("cid", cid)
+	return c
+}
+
+// TestUploadReturnsPromptlyOnShutdown exercises the chunk0-6 fix through the
+// real handler: PUT contexts are derived from the application's root
+// context, so cancelling it (as happens on shutdown) aborts an in-flight
+// Upload immediately instead of leaving it pinned to a RequestCtx that never
+// gets cancelled.
+func TestUploadReturnsPromptlyOnShutdown(t *testing.T) {
+	appCtx, cancelApp := context.WithCancel(context.Background())
+	defer cancelApp()
+
+	u := &Uploader{
+		log:            zap.NewNop(),
+		pool:           &fakePool{conn: &fakeClient{block: true}},
+		settings:       NewSettings(false, 0, 0, 0),
+		resolver:       &fakeContainerResolver{id: container.NewID()},
+		netmapCache:    newNetmapCache(time.Hour),
+		appCtx:         appCtx,
+		requestTimeout: time.Minute,
+	}
+
+	c := newUploadRequestCtx(t, "not-a-real-container-id")
+
+	done := make(chan struct{})
+	go func() {
+		u.Upload(c)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond) // let Upload start blocking in PutObject
+	cancelApp()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Upload did not return promptly after appCtx was cancelled")
+	}
+
+	if status := c.Response.StatusCode(); status != fasthttp.StatusBadGateway {
+		t.Fatalf("expected %d after the only part failed to PUT, got %d", fasthttp.StatusBadGateway, status)
+	}
+}